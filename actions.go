@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/slack-go/slack"
+)
+
+// ActionContext carries everything an Action needs to carry out its
+// side effect for a single reaction event.
+type ActionContext struct {
+	Event       Event
+	RawEvent    ReactionEvent
+	SlackClient *slack.Client
+	RedisClient *redis.Client
+	Config      *Config
+}
+
+// Action is the extension point for rule-triggered behaviors. Built-in
+// actions are registered under their type name in NewRuleEngine;
+// callers can register additional ones via RuleEngine.Register.
+type Action interface {
+	Execute(actx *ActionContext, rule *Rule) error
+}
+
+// registerBuiltinActions wires up the actions shipped with TrashCommand.
+func registerBuiltinActions(engine *RuleEngine) {
+	engine.Register("delete", deleteAction{})
+	engine.Register("timebomb", timebombAction{})
+	engine.Register("pin", pinAction{})
+	engine.Register("react", reactAction{})
+	engine.Register("forward_to_channel", forwardToChannelAction{})
+	engine.Register("post_message", postMessageAction{})
+	engine.Register("http_webhook", httpWebhookAction{})
+	engine.Register("undo", undoAction{})
+}
+
+// deleteAction removes the reacted-to message, as the original
+// wastebasket behavior did.
+type deleteAction struct{}
+
+func (deleteAction) Execute(actx *ActionContext, rule *Rule) error {
+	channel := actx.Event.Item.Channel
+	ts := actx.Event.Item.TS
+
+	if err := stashMessage(actx.SlackClient, actx.RedisClient, channel, ts, actx.Config.UndoTTLSeconds); err != nil {
+		log.Printf("Error stashing message for undo, deleting anyway: %v", err)
+	}
+
+	if err := deleteMessage(actx.RawEvent, actx.SlackClient); err != nil {
+		return err
+	}
+
+	// Slack never delivers reaction_added for a message that no longer
+	// exists, so post a confirmation for users to react to instead.
+	if undoRule := actx.Config.RuleEngine.FindByAction("undo"); undoRule != nil {
+		if err := postDeleteConfirmation(actx.SlackClient, actx.RedisClient, channel, ts, undoRule.Emoji, actx.Config.UndoTTLSeconds); err != nil {
+			log.Printf("Error posting undo confirmation: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// timebombAction hands the message off to TimeBomb for delayed deletion,
+// as the original bomb behavior did. A rule-specific `ttl` param overrides
+// config.TimeBombTTLSeconds.
+type timebombAction struct{}
+
+func (timebombAction) Execute(actx *ActionContext, rule *Rule) error {
+	config := *actx.Config
+	if ttl, ok := rule.Params["ttl"]; ok {
+		seconds, err := strconv.Atoi(ttl)
+		if err != nil {
+			return fmt.Errorf("invalid ttl param %q: %w", ttl, err)
+		}
+		config.TimeBombTTLSeconds = seconds
+	}
+	return publishToTimeBomb(actx.RawEvent, actx.RedisClient, &config)
+}
+
+// pinAction pins the reacted-to message to its channel.
+type pinAction struct{}
+
+func (pinAction) Execute(actx *ActionContext, rule *Rule) error {
+	item := slack.ItemRef{
+		Channel:   actx.Event.Item.Channel,
+		Timestamp: actx.Event.Item.TS,
+	}
+	if err := actx.SlackClient.AddPin(item.Channel, item); err != nil {
+		log.Printf("Error pinning message in channel %s: %v", item.Channel, err)
+		return err
+	}
+	log.Printf("Pinned message in channel %s", item.Channel)
+	return nil
+}
+
+// reactAction adds an additional emoji reaction to the message, keyed by
+// the rule's `emoji` param (e.g. acknowledging that an action fired).
+type reactAction struct{}
+
+func (reactAction) Execute(actx *ActionContext, rule *Rule) error {
+	emoji, ok := rule.Params["emoji"]
+	if !ok {
+		return fmt.Errorf("react action requires an \"emoji\" param")
+	}
+	item := slack.ItemRef{
+		Channel:   actx.Event.Item.Channel,
+		Timestamp: actx.Event.Item.TS,
+	}
+	if err := actx.SlackClient.AddReaction(emoji, item); err != nil {
+		log.Printf("Error adding reaction %s in channel %s: %v", emoji, item.Channel, err)
+		return err
+	}
+	log.Printf("Added reaction %s in channel %s", emoji, item.Channel)
+	return nil
+}
+
+// forwardToChannelAction re-posts a permalink to the reacted-to message
+// into the channel named by the rule's `channel` param.
+type forwardToChannelAction struct{}
+
+func (forwardToChannelAction) Execute(actx *ActionContext, rule *Rule) error {
+	target, ok := rule.Params["channel"]
+	if !ok {
+		return fmt.Errorf("forward_to_channel action requires a \"channel\" param")
+	}
+
+	permalink, err := actx.SlackClient.GetPermalink(&slack.PermalinkParameters{
+		Channel: actx.Event.Item.Channel,
+		Ts:      actx.Event.Item.TS,
+	})
+	if err != nil {
+		log.Printf("Error fetching permalink for forward: %v", err)
+		return err
+	}
+
+	_, _, err = actx.SlackClient.PostMessage(target, slack.MsgOptionText(permalink, false))
+	if err != nil {
+		log.Printf("Error forwarding message to channel %s: %v", target, err)
+		return err
+	}
+
+	log.Printf("Forwarded message %s to channel %s", actx.Event.Item.TS, target)
+	return nil
+}
+
+// postMessageAction posts a rule-defined template message, with
+// {{.User}}, {{.Channel}} and {{.Reaction}} placeholders, to the channel
+// named by the rule's `channel` param.
+type postMessageAction struct{}
+
+func (postMessageAction) Execute(actx *ActionContext, rule *Rule) error {
+	target, ok := rule.Params["channel"]
+	if !ok {
+		return fmt.Errorf("post_message action requires a \"channel\" param")
+	}
+	template := rule.Params["template"]
+	text := renderTemplate(template, actx.Event)
+
+	_, _, err := actx.SlackClient.PostMessage(target, slack.MsgOptionText(text, false))
+	if err != nil {
+		log.Printf("Error posting message to channel %s: %v", target, err)
+		return err
+	}
+
+	log.Printf("Posted message to channel %s", target)
+	return nil
+}
+
+func renderTemplate(template string, event Event) string {
+	replacer := strings.NewReplacer(
+		"{{.User}}", event.User,
+		"{{.Channel}}", event.Item.Channel,
+		"{{.Reaction}}", event.Reaction,
+	)
+	return replacer.Replace(template)
+}
+
+// httpWebhookAction POSTs the raw reaction event to the URL named by the
+// rule's `url` param, for integrating with external systems.
+type httpWebhookAction struct{}
+
+func (httpWebhookAction) Execute(actx *ActionContext, rule *Rule) error {
+	url, ok := rule.Params["url"]
+	if !ok {
+		return fmt.Errorf("http_webhook action requires a \"url\" param")
+	}
+
+	payload, err := json.Marshal(actx.RawEvent)
+	if err != nil {
+		return fmt.Errorf("marshaling webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(string(payload)))
+	if err != nil {
+		return fmt.Errorf("building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("Error calling webhook %s: %v", url, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", url, resp.StatusCode)
+	}
+
+	log.Printf("Called webhook %s", url)
+	return nil
+}
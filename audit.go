@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AuditRecord describes a single triggered action, for both the SSE
+// stream and the Redis audit history.
+type AuditRecord struct {
+	Timestamp string `json:"timestamp"`
+	Reaction  string `json:"reaction"`
+	Channel   string `json:"channel"`
+	MessageTS string `json:"message_ts"`
+	User      string `json:"user"`
+	Action    string `json:"action"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+// AuditLog fans out triggered-action records to live SSE subscribers and
+// persists them to a capped Redis stream so an ops dashboard can tail
+// activity and replay recent history.
+type AuditLog struct {
+	redisClient *redis.Client
+	streamKey   string
+	maxLen      int64
+
+	mu      sync.Mutex
+	clients map[chan AuditRecord]struct{}
+}
+
+// NewAuditLog creates an AuditLog that persists to the given Redis
+// stream key, capped to maxLen entries.
+func NewAuditLog(redisClient *redis.Client, streamKey string, maxLen int64) *AuditLog {
+	return &AuditLog{
+		redisClient: redisClient,
+		streamKey:   streamKey,
+		maxLen:      maxLen,
+		clients:     make(map[chan AuditRecord]struct{}),
+	}
+}
+
+// Record broadcasts an audit record to subscribed SSE clients and appends
+// it to the Redis audit stream.
+func (a *AuditLog) Record(record AuditRecord) {
+	record.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+
+	a.broadcast(record)
+
+	if err := a.persist(record); err != nil {
+		log.Printf("Error persisting audit record: %v", err)
+	}
+}
+
+func (a *AuditLog) broadcast(record AuditRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for ch := range a.clients {
+		select {
+		case ch <- record:
+		default:
+			log.Printf("Dropping audit record for slow SSE subscriber")
+		}
+	}
+}
+
+func (a *AuditLog) persist(record AuditRecord) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshaling audit record: %w", err)
+	}
+
+	ctx := context.Background()
+	return a.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: a.streamKey,
+		MaxLen: a.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{"record": string(payload)},
+	}).Err()
+}
+
+// subscribe registers a new SSE client and returns a channel of records
+// along with a function to unregister it.
+func (a *AuditLog) subscribe() (chan AuditRecord, func()) {
+	ch := make(chan AuditRecord, 16)
+
+	a.mu.Lock()
+	a.clients[ch] = struct{}{}
+	a.mu.Unlock()
+
+	unsubscribe := func() {
+		a.mu.Lock()
+		delete(a.clients, ch)
+		a.mu.Unlock()
+		close(ch)
+	}
+
+	return ch, unsubscribe
+}
+
+// ServeHTTP implements the /events Server-Sent Events endpoint, streaming
+// every triggered action as it happens.
+func (a *AuditLog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch, unsubscribe := a.subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case record := <-ch:
+			payload, err := json.Marshal(record)
+			if err != nil {
+				log.Printf("Error marshaling SSE record: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+		}
+	}
+}
+
+// serveAuditHTTP starts the /events SSE endpoint and blocks until ctx is
+// cancelled.
+func serveAuditHTTP(ctx context.Context, addr string, auditLog *AuditLog) {
+	mux := http.NewServeMux()
+	mux.Handle("/events", auditLog)
+
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Audit SSE endpoint listening on %s/events", addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Audit HTTP server stopped: %v", err)
+	}
+}
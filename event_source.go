@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// EventSource produces raw reaction event JSON payloads, each unmarshalable
+// into a ReactionEvent, regardless of how they were ingested.
+type EventSource interface {
+	// Events returns the channel that reaction event payloads are delivered on.
+	Events() <-chan string
+	// Run consumes from the underlying transport until ctx is cancelled or an
+	// unrecoverable error occurs.
+	Run(ctx context.Context) error
+}
+
+// RedisSource reads reaction events relayed onto a Redis pub/sub channel by
+// an external Slack-to-Redis shim. This is the original ingestion mode.
+type RedisSource struct {
+	client  *redis.Client
+	channel string
+	events  chan string
+}
+
+// NewRedisSource creates a RedisSource subscribed to the given Redis channel.
+func NewRedisSource(client *redis.Client, channel string) *RedisSource {
+	return &RedisSource{
+		client:  client,
+		channel: channel,
+		events:  make(chan string),
+	}
+}
+
+func (s *RedisSource) Events() <-chan string {
+	return s.events
+}
+
+func (s *RedisSource) Run(ctx context.Context) error {
+	pubsub := s.client.Subscribe(ctx, s.channel)
+	defer pubsub.Close()
+
+	log.Printf("Subscribed to Redis channel: %s", s.channel)
+	log.Println("Waiting for reaction events...")
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			s.events <- msg.Payload
+		}
+	}
+}
+
+// SocketModeSource connects directly to Slack over Socket Mode using a
+// SLACK_APP_TOKEN, removing the need for a separate relay that publishes
+// reaction_added events to Redis.
+type SocketModeSource struct {
+	client    *socketmode.Client
+	events    chan string
+	botUserID string
+}
+
+// NewSocketModeSource creates a SocketModeSource from an app-level token
+// (xapp-...) and a bot token. appClient must be built with
+// slack.OptionAppLevelToken(appToken).
+func NewSocketModeSource(appClient *slack.Client) *SocketModeSource {
+	return &SocketModeSource{
+		client: socketmode.New(appClient),
+		events: make(chan string),
+	}
+}
+
+func (s *SocketModeSource) Events() <-chan string {
+	return s.events
+}
+
+func (s *SocketModeSource) Run(ctx context.Context) error {
+	auth, err := s.client.AuthTestContext(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving bot user id via auth.test: %w", err)
+	}
+	s.botUserID = auth.UserID
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt, ok := <-s.client.Events:
+				if !ok {
+					return
+				}
+				s.handleSocketModeEvent(evt)
+			}
+		}
+	}()
+
+	log.Println("Connecting to Slack via Socket Mode...")
+	return s.client.RunContext(ctx)
+}
+
+func (s *SocketModeSource) handleSocketModeEvent(evt socketmode.Event) {
+	if evt.Type != socketmode.EventTypeEventsAPI {
+		return
+	}
+
+	eventsAPIEvent, ok := evt.Data.(slackevents.EventsAPIEvent)
+	if !ok {
+		log.Printf("Ignoring socket mode event with unexpected payload type")
+		return
+	}
+
+	if evt.Request != nil {
+		s.client.Ack(*evt.Request)
+	}
+
+	innerEvent := eventsAPIEvent.InnerEvent
+	switch innerEvent.Type {
+	case "reaction_added", "reaction_removed":
+		payload, err := json.Marshal(reactionEventFromInner(eventsAPIEvent, innerEvent, s.botUserID))
+		if err != nil {
+			log.Printf("Error marshaling socket mode event: %v", err)
+			return
+		}
+		s.events <- string(payload)
+	}
+}
+
+// reactionEventFromInner translates a slackevents inner event into the
+// ReactionEvent shape used throughout the rest of the service, so
+// handleMessage doesn't need to know which transport produced it.
+// botUserID populates Authorizations the same way Slack's own
+// Events API payload does for single-workspace installs, so isBot keeps
+// working identically to the Redis path.
+func reactionEventFromInner(outer slackevents.EventsAPIEvent, inner slackevents.EventsAPIInnerEvent, botUserID string) ReactionEvent {
+	event := Event{Type: inner.Type}
+
+	switch data := inner.Data.(type) {
+	case *slackevents.ReactionAddedEvent:
+		event.User = data.User
+		event.Reaction = data.Reaction
+		event.ItemUser = data.ItemUser
+		event.EventTS = data.EventTimestamp
+		event.Item = Item{
+			Type:    data.Item.Type,
+			Channel: data.Item.Channel,
+			TS:      data.Item.Timestamp,
+		}
+	case *slackevents.ReactionRemovedEvent:
+		event.User = data.User
+		event.Reaction = data.Reaction
+		event.ItemUser = data.ItemUser
+		event.EventTS = data.EventTimestamp
+		event.Item = Item{
+			Type:    data.Item.Type,
+			Channel: data.Item.Channel,
+			TS:      data.Item.Timestamp,
+		}
+	default:
+		log.Printf("Unhandled inner event data type for %s", inner.Type)
+	}
+
+	var authorizations []Auth
+	if botUserID != "" {
+		authorizations = []Auth{{UserID: botUserID, IsBot: true}}
+	}
+
+	return ReactionEvent{
+		Token:          outer.Token,
+		TeamID:         outer.TeamID,
+		APIAppID:       outer.APIAppID,
+		Type:           outer.Type,
+		Event:          event,
+		Authorizations: authorizations,
+	}
+}
+
+// newEventSource builds the configured EventSource, wiring it to either
+// Redis pub/sub or direct Slack Socket Mode depending on config.SourceMode.
+func newEventSource(config *Config, redisClient *redis.Client) (EventSource, error) {
+	switch config.SourceMode {
+	case "redis", "":
+		return NewRedisSource(redisClient, config.RedisChannel), nil
+	case "socketmode":
+		if config.SlackAppToken == "" {
+			return nil, fmt.Errorf("SLACK_APP_TOKEN environment variable is required for SOURCE_MODE=socketmode")
+		}
+		appClient := slack.New(
+			config.SlackBotToken,
+			slack.OptionAppLevelToken(config.SlackAppToken),
+		)
+		return NewSocketModeSource(appClient), nil
+	default:
+		return nil, fmt.Errorf("unknown SOURCE_MODE %q (expected \"redis\" or \"socketmode\")", config.SourceMode)
+	}
+}
@@ -49,30 +49,53 @@ type Auth struct {
 // Config holds the application configuration
 type Config struct {
 	SlackBotToken        string
+	SlackAppToken        string
+	SourceMode           string
 	RedisAddr            string
 	RedisPassword        string
 	RedisDB              int
 	RedisChannel         string
 	TimeBombRedisChannel string
 	TimeBombTTLSeconds   int
+	ReactionRulesFile    string
+	RuleEngine           *RuleEngine
+	AuditHTTPAddr        string
+	AuditStreamKey       string
+	AuditStreamMaxLen    int64
+	AuditLog             *AuditLog
+	UndoTTLSeconds       int
 }
 
 func main() {
 	// Load configuration from environment variables
 	config := Config{
 		SlackBotToken:        getEnv("SLACK_BOT_TOKEN", ""),
+		SlackAppToken:        getEnv("SLACK_APP_TOKEN", ""),
+		SourceMode:           getEnv("SOURCE_MODE", "redis"),
 		RedisAddr:            getEnv("REDIS_ADDR", "localhost:6379"),
 		RedisPassword:        getEnv("REDIS_PASSWORD", ""),
 		RedisDB:              0,
 		RedisChannel:         getEnv("REDIS_CHANNEL", "slack-relay-reaction-added"),
 		TimeBombRedisChannel: getEnv("TIMEBOMB_REDIS_CHANNEL", "timebomb-messages"),
 		TimeBombTTLSeconds:   getEnvInt("TIMEBOMB_TTL_SECONDS", 5),
+		ReactionRulesFile:    getEnv("REACTION_RULES_FILE", ""),
+		AuditHTTPAddr:        getEnv("AUDIT_HTTP_ADDR", ":8090"),
+		AuditStreamKey:       getEnv("AUDIT_STREAM_KEY", "trashcommand:audit"),
+		AuditStreamMaxLen:    int64(getEnvInt("AUDIT_STREAM_MAXLEN", 1000)),
+		UndoTTLSeconds:       getEnvInt("UNDO_TTL_SECONDS", 300),
 	}
 
 	if config.SlackBotToken == "" {
 		log.Fatal("SLACK_BOT_TOKEN environment variable is required")
 	}
 
+	ruleSet, err := loadRuleSet(config.ReactionRulesFile)
+	if err != nil {
+		log.Fatalf("Failed to load reaction rules: %v", err)
+	}
+	config.RuleEngine = NewRuleEngine(ruleSet)
+	log.Printf("Loaded %d reaction rule(s)", len(ruleSet.Rules))
+
 	// Create Slack client
 	slackClient := slack.New(config.SlackBotToken)
 
@@ -92,12 +115,15 @@ func main() {
 	}
 	log.Printf("Connected to Redis at %s", config.RedisAddr)
 
-	// Subscribe to Redis channel
-	pubsub := redisClient.Subscribe(ctx, config.RedisChannel)
-	defer pubsub.Close()
+	config.AuditLog = NewAuditLog(redisClient, config.AuditStreamKey, config.AuditStreamMaxLen)
+	go serveAuditHTTP(ctx, config.AuditHTTPAddr, config.AuditLog)
 
-	log.Printf("Subscribed to Redis channel: %s", config.RedisChannel)
-	log.Println("Waiting for reaction events...")
+	// Build the configured event source (Redis relay or direct Socket Mode)
+	source, err := newEventSource(&config, redisClient)
+	if err != nil {
+		log.Fatalf("Failed to initialize event source: %v", err)
+	}
+	log.Printf("Using event source: %s", config.SourceMode)
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -109,19 +135,24 @@ func main() {
 		cancel()
 	}()
 
-	// Listen for messages
-	ch := pubsub.Channel()
+	go func() {
+		if err := source.Run(ctx); err != nil && ctx.Err() == nil {
+			log.Fatalf("Event source stopped unexpectedly: %v", err)
+		}
+	}()
+
+	// Listen for messages, regardless of which transport produced them
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("Context cancelled, exiting")
 			return
-		case msg, ok := <-ch:
+		case payload, ok := <-source.Events():
 			if !ok {
-				log.Println("Channel closed, exiting")
+				log.Println("Event source channel closed, exiting")
 				return
 			}
-			handleMessage(msg.Payload, slackClient, redisClient, &config)
+			handleMessage(payload, slackClient, redisClient, &config)
 		}
 	}
 }
@@ -140,12 +171,6 @@ func handleMessage(payload string, slackClient *slack.Client, redisClient *redis
 		return
 	}
 
-	// Check if this is a reaction_added event
-	if event.Event.Type != "reaction_added" {
-		log.Printf("Skipping non-reaction event: %s", event.Event.Type)
-		return
-	}
-
 	// Check if the item is a message
 	if event.Event.Item.Type != "message" {
 		log.Printf("Skipping non-message item: %s", event.Event.Item.Type)
@@ -158,23 +183,72 @@ func handleMessage(payload string, slackClient *slack.Client, redisClient *redis
 		return
 	}
 
-	// Handle wastebasket reaction - delete message immediately
-	if event.Event.Reaction == "wastebasket" {
-		deleteMessage(event, slackClient)
+	rule := config.RuleEngine.Match(event.Event)
+	if rule == nil {
+		log.Printf("Skipping unsupported reaction: %s", event.Event.Reaction)
 		return
 	}
 
-	// Handle bomb reaction - publish to TimeBomb
-	if event.Event.Reaction == "bomb" {
-		publishToTimeBomb(event, redisClient, config)
+	count, window, requiresQuorum := quorumParams(rule)
+
+	// A retracted reaction only matters for rules with a quorum: let users
+	// withdraw their vote before the threshold is hit.
+	if event.Event.Type == "reaction_removed" {
+		if requiresQuorum {
+			ctx := context.Background()
+			if err := retractVote(ctx, redisClient, event.Event.Item.Channel, event.Event.Item.TS, event.Event.Reaction, event.Event.User); err != nil {
+				log.Printf("Error retracting quorum vote: %v", err)
+			}
+		}
 		return
 	}
 
-	log.Printf("Skipping unsupported reaction: %s", event.Event.Reaction)
+	if event.Event.Type != "reaction_added" {
+		log.Printf("Skipping non-reaction event: %s", event.Event.Type)
+		return
+	}
+
+	if requiresQuorum {
+		ctx := context.Background()
+		reached, err := recordVote(ctx, redisClient, event.Event.Item.Channel, event.Event.Item.TS, event.Event.Reaction, event.Event.User, count, window)
+		if err != nil {
+			log.Printf("Error recording quorum vote: %v", err)
+			return
+		}
+		if !reached {
+			return
+		}
+	}
+
+	// Dispatch to the configured rule engine, which decides what action (if
+	// any) the reaction should trigger.
+	actx := &ActionContext{
+		Event:       event.Event,
+		RawEvent:    event,
+		SlackClient: slackClient,
+		RedisClient: redisClient,
+		Config:      config,
+	}
+
+	record := AuditRecord{
+		Reaction:  event.Event.Reaction,
+		Channel:   event.Event.Item.Channel,
+		MessageTS: event.Event.Item.TS,
+		User:      event.Event.User,
+		Action:    rule.Action,
+	}
+
+	err := config.RuleEngine.Execute(actx, rule)
+	record.Success = err == nil
+	if err != nil {
+		record.Error = err.Error()
+		log.Printf("Error executing action for reaction %s: %v", event.Event.Reaction, err)
+	}
+	config.AuditLog.Record(record)
 }
 
 // deleteMessage deletes a Slack message immediately
-func deleteMessage(event ReactionEvent, slackClient *slack.Client) {
+func deleteMessage(event ReactionEvent, slackClient *slack.Client) error {
 	channel := event.Event.Item.Channel
 	timestamp := event.Event.Item.TS
 
@@ -183,14 +257,15 @@ func deleteMessage(event ReactionEvent, slackClient *slack.Client) {
 	_, _, err := slackClient.DeleteMessage(channel, timestamp)
 	if err != nil {
 		log.Printf("Error deleting message: %v", err)
-		return
+		return err
 	}
 
 	log.Printf("Successfully deleted message in channel %s", channel)
+	return nil
 }
 
 // publishToTimeBomb publishes a message to the TimeBomb Redis channel
-func publishToTimeBomb(event ReactionEvent, redisClient *redis.Client, config *Config) {
+func publishToTimeBomb(event ReactionEvent, redisClient *redis.Client, config *Config) error {
 	channel := event.Event.Item.Channel
 	timestamp := event.Event.Item.TS
 
@@ -203,17 +278,18 @@ func publishToTimeBomb(event ReactionEvent, redisClient *redis.Client, config *C
 	payload, err := json.Marshal(message)
 	if err != nil {
 		log.Printf("Error marshaling TimeBomb message: %v", err)
-		return
+		return err
 	}
 
 	ctx := context.Background()
 	err = redisClient.Publish(ctx, config.TimeBombRedisChannel, string(payload)).Err()
 	if err != nil {
 		log.Printf("Error publishing to TimeBomb: %v", err)
-		return
+		return err
 	}
 
 	log.Printf("Published message to TimeBomb: channel=%s, ts=%s, ttl=%ds", channel, timestamp, config.TimeBombTTLSeconds)
+	return nil
 }
 
 // isBot checks if the user who reacted is a bot
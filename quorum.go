@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// quorumParams returns the configured reactor threshold and voting window
+// for a rule, or ok=false if the rule doesn't require a quorum (the
+// action should fire on the first matching reaction, as before).
+func quorumParams(rule *Rule) (count int, window time.Duration, ok bool) {
+	raw, present := rule.Params["quorum"]
+	if !present {
+		return 0, 0, false
+	}
+
+	count, err := strconv.Atoi(raw)
+	if err != nil || count <= 1 {
+		return 0, 0, false
+	}
+
+	window = 60 * time.Second
+	if raw, present := rule.Params["window"]; present {
+		parsed, err := time.ParseDuration(raw)
+		if err == nil {
+			window = parsed
+		}
+	}
+
+	return count, window, true
+}
+
+// quorumKey identifies the Redis set tracking distinct reactors for a
+// given message and reaction, e.g. "quorum:C123:1690000000.000100:bomb".
+func quorumKey(channel, ts, reaction string) string {
+	return fmt.Sprintf("trashcommand:quorum:%s:%s:%s", channel, ts, reaction)
+}
+
+// quorumFiredKey identifies the Redis key marking that a quorum's action
+// has already been executed for a given message and reaction, so later
+// reactors are no-ops instead of re-triggering the action.
+func quorumFiredKey(channel, ts, reaction string) string {
+	return fmt.Sprintf("trashcommand:quorum:fired:%s:%s:%s", channel, ts, reaction)
+}
+
+// recordVote adds user to the reactor set for (channel, ts, reaction),
+// refreshes the set's TTL to window, and reports whether this call is the
+// one that newly reached count distinct reactors. Once the quorum has
+// fired once, a "fired" marker key (set atomically via SETNX so
+// concurrent voters can't both win) makes every subsequent vote report
+// reached=false, so the action is only executed once.
+func recordVote(ctx context.Context, redisClient *redis.Client, channel, ts, reaction, user string, count int, window time.Duration) (bool, error) {
+	key := quorumKey(channel, ts, reaction)
+
+	pipe := redisClient.TxPipeline()
+	pipe.SAdd(ctx, key, user)
+	pipe.Expire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("recording quorum vote: %w", err)
+	}
+
+	size, err := redisClient.SCard(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("reading quorum set size: %w", err)
+	}
+
+	if size < int64(count) {
+		return false, nil
+	}
+
+	firedKey := quorumFiredKey(channel, ts, reaction)
+	won, err := redisClient.SetNX(ctx, firedKey, "1", window).Result()
+	if err != nil {
+		return false, fmt.Errorf("marking quorum as fired: %w", err)
+	}
+	if !won {
+		log.Printf("Quorum for %s already fired, ignoring vote from %s", key, user)
+		return false, nil
+	}
+
+	log.Printf("Quorum vote recorded for %s: %d/%d reactors, firing action", key, size, count)
+	return true, nil
+}
+
+// retractVote removes user from the reactor set, letting them undo a vote
+// before a quorum's threshold is reached.
+func retractVote(ctx context.Context, redisClient *redis.Client, channel, ts, reaction, user string) error {
+	key := quorumKey(channel, ts, reaction)
+	if err := redisClient.SRem(ctx, key, user).Err(); err != nil {
+		return fmt.Errorf("retracting quorum vote: %w", err)
+	}
+	log.Printf("Quorum vote retracted for %s by %s", key, user)
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	return redis.NewClient(&redis.Options{Addr: server.Addr()})
+}
+
+// TestRecordVoteFiresOnce reproduces the scenario from the quorum review
+// comment: once a quorum is reached, later reactors on the same message
+// must not cause the action to be reported as "reached" again.
+func TestRecordVoteFiresOnce(t *testing.T) {
+	client := newTestRedis(t)
+	ctx := context.Background()
+
+	const (
+		channel  = "C123"
+		ts       = "1690000000.000100"
+		reaction = "wastebasket"
+		count    = 3
+	)
+	window := 60 * time.Second
+
+	reached := make([]bool, 0, 4)
+	for i, user := range []string{"alice", "bob", "carol", "dave"} {
+		r, err := recordVote(ctx, client, channel, ts, reaction, user, count, window)
+		if err != nil {
+			t.Fatalf("recordVote #%d: unexpected error: %v", i, err)
+		}
+		reached = append(reached, r)
+	}
+
+	want := []bool{false, false, true, false}
+	for i, got := range reached {
+		if got != want[i] {
+			t.Errorf("reached[%d] = %v, want %v (full: %v)", i, got, want[i], reached)
+		}
+	}
+}
+
+// TestRecordVoteDedupesUser ensures the same user voting twice doesn't
+// inflate the reactor count.
+func TestRecordVoteDedupesUser(t *testing.T) {
+	client := newTestRedis(t)
+	ctx := context.Background()
+
+	reached, err := recordVote(ctx, client, "C123", "1.1", "bomb", "alice", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reached {
+		t.Fatalf("quorum should not be reached after a single voter")
+	}
+
+	reached, err = recordVote(ctx, client, "C123", "1.1", "bomb", "alice", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reached {
+		t.Fatalf("quorum should not be reached after the same voter votes twice")
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule describes a single emoji-triggered behavior: which reaction it
+// matches, who is allowed (or denied) to trigger it, and what action to
+// take when it fires.
+type Rule struct {
+	Emoji         string            `yaml:"emoji" json:"emoji"`
+	Action        string            `yaml:"action" json:"action"`
+	Params        map[string]string `yaml:"params" json:"params"`
+	AllowUsers    []string          `yaml:"allow_users" json:"allow_users"`
+	DenyUsers     []string          `yaml:"deny_users" json:"deny_users"`
+	AllowChannels []string          `yaml:"allow_channels" json:"allow_channels"`
+	DenyChannels  []string          `yaml:"deny_channels" json:"deny_channels"`
+}
+
+// RuleSet is the top-level shape of a REACTION_RULES_FILE document.
+type RuleSet struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// defaultRuleSet preserves the original hard-coded behavior
+// (wastebasket -> delete, bomb -> timebomb) for deployments that don't set
+// REACTION_RULES_FILE.
+func defaultRuleSet() *RuleSet {
+	return &RuleSet{
+		Rules: []Rule{
+			{Emoji: "wastebasket", Action: "delete"},
+			{Emoji: "bomb", Action: "timebomb"},
+			{Emoji: "leftwards_arrow_with_hook", Action: "undo"},
+		},
+	}
+}
+
+// loadRuleSet reads and parses a rules file, selecting a YAML or JSON
+// decoder based on its extension.
+func loadRuleSet(path string) (*RuleSet, error) {
+	if path == "" {
+		return defaultRuleSet(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading rules file %s: %w", path, err)
+	}
+
+	var set RuleSet
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &set)
+	default:
+		err = yaml.Unmarshal(data, &set)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing rules file %s: %w", path, err)
+	}
+
+	return &set, nil
+}
+
+// RuleEngine matches incoming reaction events against a RuleSet and
+// dispatches to the registered Action for each match.
+type RuleEngine struct {
+	ruleSet *RuleSet
+	actions map[string]Action
+}
+
+// NewRuleEngine builds a RuleEngine seeded with the built-in actions.
+func NewRuleEngine(ruleSet *RuleSet) *RuleEngine {
+	engine := &RuleEngine{
+		ruleSet: ruleSet,
+		actions: make(map[string]Action),
+	}
+	registerBuiltinActions(engine)
+	return engine
+}
+
+// Register adds or replaces the Action used for a given action type name,
+// allowing extensions to add new emoji-triggered behaviors without
+// recompiling the dispatch logic.
+func (e *RuleEngine) Register(actionType string, action Action) {
+	e.actions[actionType] = action
+}
+
+// Dispatch finds the first rule matching the event's reaction, user and
+// channel, and executes its action. It returns false if no rule matched.
+func (e *RuleEngine) Dispatch(actx *ActionContext) (bool, error) {
+	rule := e.Match(actx.Event)
+	if rule == nil {
+		return false, nil
+	}
+
+	return true, e.Execute(actx, rule)
+}
+
+// Execute runs the action for an already-matched rule, without
+// re-matching. Used once a quorum has been reached separately.
+func (e *RuleEngine) Execute(actx *ActionContext, rule *Rule) error {
+	action, ok := e.actions[rule.Action]
+	if !ok {
+		return fmt.Errorf("no action registered for type %q", rule.Action)
+	}
+	return action.Execute(actx, rule)
+}
+
+// FindByAction returns the first configured rule with the given action
+// type, or nil if none is configured. Used by actions that need to know
+// how another rule is configured, e.g. the delete action looking up which
+// emoji the undo action listens for.
+func (e *RuleEngine) FindByAction(action string) *Rule {
+	for i := range e.ruleSet.Rules {
+		if e.ruleSet.Rules[i].Action == action {
+			return &e.ruleSet.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Match returns the first rule matching the event's reaction, user and
+// channel, or nil if none match.
+func (e *RuleEngine) Match(event Event) *Rule {
+	for i := range e.ruleSet.Rules {
+		rule := &e.ruleSet.Rules[i]
+		if rule.Emoji != event.Reaction {
+			continue
+		}
+		if !rule.allows(event.User, event.Item.Channel) {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+func (r *Rule) allows(user, channel string) bool {
+	if len(r.AllowUsers) > 0 && !contains(r.AllowUsers, user) {
+		return false
+	}
+	if contains(r.DenyUsers, user) {
+		return false
+	}
+	if len(r.AllowChannels) > 0 && !contains(r.AllowChannels, channel) {
+		return false
+	}
+	if contains(r.DenyChannels, channel) {
+		return false
+	}
+	return true
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
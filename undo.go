@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/slack-go/slack"
+)
+
+// StashedFile is the subset of a Slack file's metadata worth keeping
+// around for an undo: enough to reference or re-link it, since the
+// underlying file object generally outlives the message it was shared on.
+type StashedFile struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Permalink string `json:"permalink"`
+}
+
+// StashedMessage is the original content of a message slated for deletion,
+// kept around just long enough for its author (or an admin) to undo the
+// delete.
+type StashedMessage struct {
+	Channel     string             `json:"channel"`
+	TS          string             `json:"ts"`
+	User        string             `json:"user"`
+	Text        string             `json:"text"`
+	Attachments []slack.Attachment `json:"attachments,omitempty"`
+	Blocks      slack.Blocks       `json:"blocks,omitempty"`
+	Files       []StashedFile      `json:"files,omitempty"`
+}
+
+// undoKey identifies the Redis key a stashed message's content is held
+// under, keyed by the original message's own (channel, ts).
+func undoKey(channel, ts string) string {
+	return fmt.Sprintf("undo:%s:%s", channel, ts)
+}
+
+// undoConfirmKey identifies the Redis key mapping a posted undo
+// confirmation message back to the original message it stands in for.
+// Slack never delivers reaction_added for a message that no longer
+// exists, so the confirmation message - not the deleted one - is what
+// users actually react to.
+func undoConfirmKey(channel, confirmTS string) string {
+	return fmt.Sprintf("undo:confirm:%s:%s", channel, confirmTS)
+}
+
+// stashMessage fetches the message at (channel, ts) via conversations.history
+// and stores its content in Redis under a TTL-bound key, so it can be
+// restored later if the delete is undone. A zero or negative ttlSeconds
+// disables stashing.
+func stashMessage(slackClient *slack.Client, redisClient *redis.Client, channel, ts string, ttlSeconds int) error {
+	if ttlSeconds <= 0 {
+		return nil
+	}
+
+	history, err := slackClient.GetConversationHistory(&slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Latest:    ts,
+		Inclusive: true,
+		Limit:     1,
+	})
+	if err != nil {
+		return fmt.Errorf("fetching message history for %s/%s: %w", channel, ts, err)
+	}
+	if len(history.Messages) == 0 {
+		return fmt.Errorf("message %s/%s not found in conversation history", channel, ts)
+	}
+
+	msg := history.Messages[0]
+	stashed := StashedMessage{
+		Channel:     channel,
+		TS:          ts,
+		User:        msg.User,
+		Text:        msg.Text,
+		Attachments: msg.Attachments,
+		Blocks:      msg.Blocks,
+		Files:       stashFiles(msg.Files),
+	}
+
+	payload, err := json.Marshal(stashed)
+	if err != nil {
+		return fmt.Errorf("marshaling stashed message: %w", err)
+	}
+
+	ctx := context.Background()
+	ttl := time.Duration(ttlSeconds) * time.Second
+	if err := redisClient.Set(ctx, undoKey(channel, ts), payload, ttl).Err(); err != nil {
+		return fmt.Errorf("stashing message %s/%s: %w", channel, ts, err)
+	}
+
+	return nil
+}
+
+func stashFiles(files []slack.File) []StashedFile {
+	if len(files) == 0 {
+		return nil
+	}
+
+	stashed := make([]StashedFile, len(files))
+	for i, f := range files {
+		stashed[i] = StashedFile{ID: f.ID, Name: f.Name, Permalink: f.Permalink}
+	}
+	return stashed
+}
+
+// postDeleteConfirmation posts a short-lived message announcing the
+// delete and records the mapping from that message's own ts back to the
+// original message's stashed undo entry, so a later reaction on the
+// confirmation - the only message left for users to react to - can find
+// it. A zero or negative ttlSeconds skips posting a confirmation
+// entirely, leaving the delete irreversible.
+func postDeleteConfirmation(slackClient *slack.Client, redisClient *redis.Client, channel, originalTS, undoEmoji string, ttlSeconds int) error {
+	if ttlSeconds <= 0 {
+		return nil
+	}
+
+	ttl := time.Duration(ttlSeconds) * time.Second
+	text := fmt.Sprintf("Message deleted. React with :%s: within %s to undo.", undoEmoji, ttl)
+
+	_, confirmTS, err := slackClient.PostMessage(channel, slack.MsgOptionText(text, false))
+	if err != nil {
+		return fmt.Errorf("posting undo confirmation to %s: %w", channel, err)
+	}
+
+	ctx := context.Background()
+	if err := redisClient.Set(ctx, undoConfirmKey(channel, confirmTS), originalTS, ttl).Err(); err != nil {
+		return fmt.Errorf("storing undo confirmation mapping for %s/%s: %w", channel, confirmTS, err)
+	}
+
+	return nil
+}
+
+// undoAction restores a previously deleted message's content when its
+// undo emoji is applied, by the original author or an admin, to the
+// delete's confirmation message within the undo TTL.
+type undoAction struct{}
+
+func (undoAction) Execute(actx *ActionContext, rule *Rule) error {
+	channel := actx.Event.Item.Channel
+	confirmTS := actx.Event.Item.TS
+
+	ctx := context.Background()
+	originalTS, err := actx.RedisClient.Get(ctx, undoConfirmKey(channel, confirmTS)).Result()
+	if err == redis.Nil {
+		log.Printf("No pending undo for confirmation message %s/%s (expired, already used, or not an undo confirmation)", channel, confirmTS)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading undo confirmation mapping for %s/%s: %w", channel, confirmTS, err)
+	}
+
+	raw, err := actx.RedisClient.Get(ctx, undoKey(channel, originalTS)).Result()
+	if err == redis.Nil {
+		log.Printf("No undo entry for %s/%s (expired)", channel, originalTS)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading undo entry for %s/%s: %w", channel, originalTS, err)
+	}
+
+	var stashed StashedMessage
+	if err := json.Unmarshal([]byte(raw), &stashed); err != nil {
+		return fmt.Errorf("unmarshaling stashed message: %w", err)
+	}
+
+	allowed, err := undoAuthorized(actx.SlackClient, actx.Event.User, stashed.User)
+	if err != nil {
+		return fmt.Errorf("checking undo authorization: %w", err)
+	}
+	if !allowed {
+		log.Printf("User %s is not authorized to undo delete of %s/%s", actx.Event.User, channel, originalTS)
+		return nil
+	}
+
+	_, _, err = actx.SlackClient.PostMessage(
+		channel,
+		slack.MsgOptionText(restoredText(stashed), false),
+		slack.MsgOptionAttachments(stashed.Attachments...),
+		slack.MsgOptionBlocks(stashed.Blocks.BlockSet...),
+	)
+	if err != nil {
+		return fmt.Errorf("reposting undone message to %s: %w", channel, err)
+	}
+
+	if err := actx.RedisClient.Del(ctx, undoKey(channel, originalTS), undoConfirmKey(channel, confirmTS)).Err(); err != nil {
+		log.Printf("Error clearing undo entries for %s/%s: %v", channel, originalTS, err)
+	}
+
+	if _, _, err := actx.SlackClient.DeleteMessage(channel, confirmTS); err != nil {
+		log.Printf("Error deleting undo confirmation message %s/%s: %v", channel, confirmTS, err)
+	}
+
+	log.Printf("Restored message in channel %s originally authored by %s", channel, stashed.User)
+	return nil
+}
+
+// restoredText appends a reference to any files the original message had,
+// since files generally can't be re-attached to a freshly posted message.
+func restoredText(stashed StashedMessage) string {
+	if len(stashed.Files) == 0 {
+		return stashed.Text
+	}
+
+	var links strings.Builder
+	links.WriteString(stashed.Text)
+	links.WriteString("\n\nAttached files:")
+	for _, f := range stashed.Files {
+		fmt.Fprintf(&links, "\n• %s: %s", f.Name, f.Permalink)
+	}
+	return links.String()
+}
+
+// undoAuthorized reports whether reactingUser may undo a delete of a
+// message originally posted by originalAuthor: either they're the same
+// person, or the reacting user is a workspace admin/owner.
+func undoAuthorized(slackClient *slack.Client, reactingUser, originalAuthor string) (bool, error) {
+	if reactingUser == originalAuthor {
+		return true, nil
+	}
+
+	user, err := slackClient.GetUserInfo(reactingUser)
+	if err != nil {
+		return false, err
+	}
+
+	return user.IsAdmin || user.IsOwner, nil
+}